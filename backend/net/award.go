@@ -0,0 +1,135 @@
+package net
+
+import (
+	"backend/tools"
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AwardCategory identifies the reason an award was granted
+type AwardCategory = uint8
+
+const (
+	AwardCorrectAnswer AwardCategory = iota // Awarded for answering correctly
+	AwardStreakBonus                        // Awarded for maintaining an answer streak
+	AwardSpeedBonus                         // Awarded for answering quickly
+	AwardPenalty                            // Recorded for an answer that earned no credit
+)
+
+// Award is a single scoring event recorded in a game's append-only award log
+type Award struct {
+	Timestamp time.Time     `json:"timestamp"` // When the award was granted
+	PlayerId  string        `json:"playerId"`  // The id of the player the award applies to
+	Category  AwardCategory `json:"category"`  // The reason for the award
+	Points    int32         `json:"points"`    // The points granted (or deducted, if negative)
+}
+
+// AwardLog is an append-only, disk-backed log of every scoring event in a
+// game. Scores are derived by folding the log rather than tracked separately,
+// so a crashed server can reconstruct final scores by replaying the log on
+// restart
+type AwardLog struct {
+	mutex   sync.Mutex
+	awards  []Award
+	encoder *json.Encoder
+	file    *os.File
+}
+
+// NewAwardLog opens (creating if necessary) the award log file for the
+// provided game id within dir, ready to append new awards
+func NewAwardLog(dir string, gameId string) (*AwardLog, error) {
+	path := filepath.Join(dir, gameId+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &AwardLog{encoder: json.NewEncoder(file), file: file}, nil
+}
+
+// LoadAwardLog reconstructs an award log from disk, replaying each line to
+// restore state after a server restart
+func LoadAwardLog(dir string, gameId string) (*AwardLog, error) {
+	path := filepath.Join(dir, gameId+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	log := &AwardLog{encoder: json.NewEncoder(file), file: file}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var award Award
+		if err := json.Unmarshal(scanner.Bytes(), &award); err != nil {
+			return nil, err
+		}
+		log.awards = append(log.awards, award)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// Append records a new award, persisting it to disk before returning
+func (l *AwardLog) Append(award Award) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.awards = append(l.awards, award)
+	return l.encoder.Encode(award)
+}
+
+// Scores folds the award log into a map of player id to total score
+func (l *AwardLog) Scores() map[string]int32 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	scores := make(map[string]int32)
+	for _, award := range l.awards {
+		scores[award.PlayerId] += award.Points
+	}
+	return scores
+}
+
+// ScoresPacket folds the award log into a tools.ScoreMap and wraps it in an
+// SScores packet, ready to broadcast
+func (l *AwardLog) ScoresPacket() Packet {
+	folded := l.Scores()
+	scores := make(tools.ScoreMap, len(folded))
+	for id, points := range folded {
+		scores[id] = points
+	}
+	return ScoresPacket(scores)
+}
+
+// All returns every award recorded in the log, in the order they occurred
+func (l *AwardLog) All() []Award {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	awards := make([]Award, len(l.awards))
+	copy(awards, l.awards)
+	return awards
+}
+
+// Close flushes and closes the underlying award log file
+func (l *AwardLog) Close() error {
+	return l.file.Close()
+}
+
+// AwardLogPacket creates a new packet announcing a single award as it happens,
+// for spectators and hosts watching the live feed
+func AwardLogPacket(award Award) Packet {
+	return Packet{Id: SAwardLog, Data: struct {
+		Award Award `json:"award"`
+	}{Award: award}}
+}
+
+// ReplayPacket creates a new packet streaming the full award log, sent to a
+// late-joining spectator to catch them up, or to the host at game-end for
+// export
+func ReplayPacket(awards []Award) Packet {
+	return Packet{Id: SReplay, Data: struct {
+		Awards []Award `json:"awards"`
+	}{Awards: awards}}
+}
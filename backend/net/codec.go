@@ -0,0 +1,88 @@
+package net
+
+import (
+	"backend/tools"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/google/uuid"
+	. "github.com/jacobtread/gowsps"
+)
+
+// Capabilities is sent by the client immediately after connecting to
+// advertise which optional features it supports. A client that does not send
+// this packet is assumed to support JSON framing only
+type Capabilities struct {
+	Binary bool `json:"binary"` // Whether the client can decode the binary-framed variants of STimeSync and SScores
+}
+
+// EncodePacket returns the wire bytes for packet, chosen per-connection from
+// caps rather than a global override: STimeSync and SScores use the compact
+// binary encoding only when caps.Binary is set, and every other packet (or a
+// client that never sent CCapabilities) falls back to standard JSON framing
+func EncodePacket(packet Packet, caps Capabilities) ([]byte, error) {
+	if caps.Binary {
+		switch packet.Id {
+		case STimeSync:
+			if data, ok := packet.Data.(struct {
+				Total     int64 `json:"total"`
+				Remaining int64 `json:"remaining"`
+			}); ok {
+				return encodeTimeSync(data), nil
+			}
+		case SScores:
+			if data, ok := packet.Data.(struct {
+				Scores tools.ScoreMap `json:"scores"`
+			}); ok {
+				return encodeScores(data), nil
+			}
+		}
+	}
+	return json.Marshal(packet)
+}
+
+// encodeTimeSync writes STimeSync as a 1-byte packet id followed by two
+// varint millisecond values (total, remaining)
+func encodeTimeSync(data struct {
+	Total     int64 `json:"total"`
+	Remaining int64 `json:"remaining"`
+}) []byte {
+	buf := make([]byte, 1+2*binary.MaxVarintLen64)
+	buf[0] = byte(STimeSync)
+	n := 1
+	n += binary.PutVarint(buf[n:], data.Total)
+	n += binary.PutVarint(buf[n:], data.Remaining)
+	return buf[:n]
+}
+
+// encodeScores writes SScores as a 1-byte packet id, a varint player count,
+// then for each player a 16-byte uuid followed by a varint score. Player ids
+// that aren't valid uuids are dropped before the count is written, so the
+// header always matches the number of pairs that follow
+func encodeScores(data struct {
+	Scores tools.ScoreMap `json:"scores"`
+}) []byte {
+	entries := make([]binaryScore, 0, len(data.Scores))
+	for id, score := range data.Scores {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, binaryScore{id: parsed, score: score})
+	}
+
+	buf := make([]byte, 1+binary.MaxVarintLen64+len(entries)*(16+binary.MaxVarintLen64))
+	buf[0] = byte(SScores)
+	n := 1
+	n += binary.PutUvarint(buf[n:], uint64(len(entries)))
+	for _, entry := range entries {
+		n += copy(buf[n:], entry.id[:])
+		n += binary.PutUvarint(buf[n:], uint64(entry.score))
+	}
+	return buf[:n]
+}
+
+// binaryScore pairs a parsed player uuid with their score, ready to encode
+type binaryScore struct {
+	id    uuid.UUID
+	score int32
+}
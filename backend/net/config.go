@@ -0,0 +1,129 @@
+package net
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// GameConfig holds the host-configurable timing settings for a game. A config
+// is resolved once at game creation time with the following priority: values
+// supplied on the create request, then values from the server's config file,
+// then these compiled defaults
+type GameConfig struct {
+	TickIntervalMs    int64   `json:"tickIntervalMs"`    // Interval between server game ticks in milliseconds
+	Timescale         float32 `json:"timescale"`         // Multiplier applied to all countdowns (1.0 = normal speed)
+	QuestionCountdown int64   `json:"questionCountdown"` // Countdown shown before a question starts, in milliseconds
+	AnswerRevealDelay int64   `json:"answerRevealDelay"` // Delay before revealing the correct answer, in milliseconds
+	ScoreDecayCurve   float32 `json:"scoreDecayCurve"`   // Exponent controlling how quickly the speed bonus decays
+}
+
+// DefaultGameConfig returns the compiled-in default config used when neither
+// the create request nor the server config file provide a value
+func DefaultGameConfig() GameConfig {
+	return GameConfig{
+		TickIntervalMs:    100,
+		Timescale:         1,
+		QuestionCountdown: 3000,
+		AnswerRevealDelay: 1500,
+		ScoreDecayCurve:   1,
+	}
+}
+
+// GameConfigOverride mirrors GameConfig but with every field optional, so a
+// host's create request or the server's config file can override a single
+// setting - including to an explicit zero, such as ScoreDecayCurve: 0 for "no
+// decay" - without needing to repeat every other value
+type GameConfigOverride struct {
+	TickIntervalMs    *int64   `json:"tickIntervalMs,omitempty"`
+	Timescale         *float32 `json:"timescale,omitempty"`
+	QuestionCountdown *int64   `json:"questionCountdown,omitempty"`
+	AnswerRevealDelay *int64   `json:"answerRevealDelay,omitempty"`
+	ScoreDecayCurve   *float32 `json:"scoreDecayCurve,omitempty"`
+}
+
+// ResolveGameConfig merges the host's create-time request, the server's
+// config file and the compiled defaults, in that priority order: a field left
+// nil in request or file falls through to the next source
+func ResolveGameConfig(request *GameConfigOverride, file *GameConfigOverride) GameConfig {
+	config := DefaultGameConfig()
+	if file != nil {
+		config = overlayGameConfig(config, *file)
+	}
+	if request != nil {
+		config = overlayGameConfig(config, *request)
+	}
+	return config
+}
+
+// overlayGameConfig returns base with every set field of override applied on
+// top of it
+func overlayGameConfig(base GameConfig, override GameConfigOverride) GameConfig {
+	if override.TickIntervalMs != nil {
+		base.TickIntervalMs = *override.TickIntervalMs
+	}
+	if override.Timescale != nil {
+		base.Timescale = *override.Timescale
+	}
+	if override.QuestionCountdown != nil {
+		base.QuestionCountdown = *override.QuestionCountdown
+	}
+	if override.AnswerRevealDelay != nil {
+		base.AnswerRevealDelay = *override.AnswerRevealDelay
+	}
+	if override.ScoreDecayCurve != nil {
+		base.ScoreDecayCurve = *override.ScoreDecayCurve
+	}
+	return base
+}
+
+// QuestionCountdownDuration returns the question countdown scaled by the
+// config's timescale, ready to pass to TimeSyncPacket
+func (c GameConfig) QuestionCountdownDuration() time.Duration {
+	return time.Duration(float32(c.QuestionCountdown) * c.Timescale * float32(time.Millisecond))
+}
+
+// AnswerRevealDelayDuration returns the answer reveal delay scaled by the
+// config's timescale
+func (c GameConfig) AnswerRevealDelayDuration() time.Duration {
+	return time.Duration(float32(c.AnswerRevealDelay) * c.Timescale * float32(time.Millisecond))
+}
+
+// DecodeGameConfigRequest parses the optional JSON config body of a game
+// create request and resolves it against the server's file config and the
+// compiled defaults. A missing or empty body resolves to fileConfig alone
+func DecodeGameConfigRequest(body io.Reader, fileConfig GameConfigOverride) (GameConfig, error) {
+	var request GameConfigOverride
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		if err == io.EOF {
+			return ResolveGameConfig(nil, &fileConfig), nil
+		}
+		return GameConfig{}, err
+	}
+	return ResolveGameConfig(&request, &fileConfig), nil
+}
+
+// CreateGameConfigHandler wraps DecodeGameConfigRequest as an http.HandlerFunc
+// for mounting at the game-create endpoint: it resolves the posted config and
+// replies with the value that will be used for the new game
+func CreateGameConfigHandler(fileConfig GameConfigOverride) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		config, err := DecodeGameConfigRequest(request.Body, fileConfig)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(config)
+	}
+}
+
+// GameConfigPacket creates a new packet sent to all joiners containing the
+// resolved timing config for the game so clients can animate countdowns at
+// the correct cadence
+func GameConfigPacket(config GameConfig) Packet {
+	return Packet{Id: SGameConfig, Data: struct {
+		Config GameConfig `json:"config"`
+	}{Config: config}}
+}
@@ -0,0 +1,209 @@
+package net
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EventType identifies a game lifecycle event that can be subscribed to on
+// the EventBus
+type EventType = uint8
+
+const (
+	PlayerJoined   EventType = iota // A player joined the game
+	PlayerAnswered                  // A player submitted an answer
+	QuestionStarted                 // A question became active
+	QuestionEnded                   // A question's answer window closed
+	GameEnded                       // The game finished
+)
+
+// Event is a single occurrence published on the EventBus
+type Event struct {
+	GameId string    `json:"gameId"` // The id of the game the event occurred in
+	Type   EventType `json:"type"`   // The kind of event
+	Data   any       `json:"data"`   // Event-specific payload
+}
+
+// EventHandler receives events from the EventBus
+type EventHandler func(event Event)
+
+// subscription pairs a handler with the id used to remove it again
+type subscription struct {
+	id      uint64
+	handler EventHandler
+}
+
+// EventBus fans out game lifecycle events to subscribed handlers, for use by
+// external integrations (tournament tooling, OBS overlays, Discord bots)
+// without them needing to reverse-engineer the websocket protocol
+type EventBus struct {
+	mutex    sync.Mutex
+	nextId   uint64
+	handlers map[EventType][]subscription
+}
+
+// NewEventBus creates a new, empty EventBus
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[EventType][]subscription)}
+}
+
+// On registers a handler to be invoked whenever an event of the provided type
+// is fired. The returned id can be passed to Off to remove just this handler
+func (b *EventBus) On(eventType EventType, handler EventHandler) uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.nextId++
+	id := b.nextId
+	b.handlers[eventType] = append(b.handlers[eventType], subscription{id: id, handler: handler})
+	return id
+}
+
+// Off removes the single handler previously registered with On under id,
+// leaving every other subscriber for that event type untouched
+func (b *EventBus) Off(eventType EventType, id uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	subs := b.handlers[eventType]
+	for i, sub := range subs {
+		if sub.id == id {
+			b.handlers[eventType] = append(subs[:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Fire dispatches event to every handler registered for its type. Each
+// handler runs in its own goroutine so a slow or stuck subscriber (for
+// example an SSE client that stopped reading) cannot stall delivery to other
+// subscribers or block the caller
+func (b *EventBus) Fire(event Event) {
+	b.mutex.Lock()
+	subs := b.handlers[event.Type]
+	handlers := make([]EventHandler, len(subs))
+	for i, sub := range subs {
+		handlers[i] = sub.handler
+	}
+	b.mutex.Unlock()
+
+	for _, handler := range handlers {
+		go handler(event)
+	}
+}
+
+// ServeEvents returns an http.HandlerFunc that streams every event fired on
+// the bus to the client as server-sent-events, for mounting at a path such as
+// /events/{gameId}. Its subscriptions are removed the moment the client
+// disconnects, so a connection never outlives its handlers
+func (b *EventBus) ServeEvents() http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		flusher, ok := writer.(http.Flusher)
+		if !ok {
+			http.Error(writer, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.Header().Set("Cache-Control", "no-cache")
+		writer.Header().Set("Connection", "keep-alive")
+
+		events := make(chan Event, 16)
+		send := func(event Event) {
+			select {
+			case events <- event:
+			default: // the client is behind; drop rather than leave this handler's goroutine blocked
+			}
+		}
+
+		ids := make(map[EventType]uint64, 5)
+		for eventType := PlayerJoined; eventType <= GameEnded; eventType++ {
+			ids[eventType] = b.On(eventType, send)
+		}
+		defer func() {
+			for eventType, id := range ids {
+				b.Off(eventType, id)
+			}
+		}()
+
+		for {
+			select {
+			case event := <-events:
+				payload, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(writer, "data: %s\n\n", payload)
+				flusher.Flush()
+			case <-request.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// WebhookSubscription delivers every event fired on a bus as a signed HTTP
+// POST, for integrations that can't hold a long-lived SSE connection open
+type WebhookSubscription struct {
+	URL    string
+	Secret string
+}
+
+// SubscribeWebhook registers sub to receive every event fired on the bus as a
+// signed POST to sub.URL. Delivery runs on Fire's per-handler goroutine, so a
+// slow or unreachable endpoint cannot stall other subscribers
+func (b *EventBus) SubscribeWebhook(sub WebhookSubscription) {
+	for eventType := PlayerJoined; eventType <= GameEnded; eventType++ {
+		b.On(eventType, func(event Event) { deliverWebhook(sub, event) })
+	}
+}
+
+// deliverWebhook POSTs event to sub.URL with an X-Quizler-Signature header,
+// retrying a handful of times with backoff if the endpoint is unreachable or
+// returns a server error
+func deliverWebhook(sub WebhookSubscription, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	signature := SignWebhookPayload(sub.Secret, payload)
+
+	const maxAttempts = 3
+	backoff := time.Second
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		request, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Quizler-Signature", signature)
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			continue
+		}
+		response.Body.Close()
+		if response.StatusCode < 500 {
+			return
+		}
+	}
+}
+
+// SignWebhookPayload signs payload with the provided shared secret using
+// HMAC-SHA256, for inclusion in the X-Quizler-Signature header of a webhook
+// delivery so subscribers can verify events actually came from this server
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,267 @@
+package net
+
+import (
+	"backend/tools"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownConnection is returned when an operation references a
+// connection id that isn't part of the game
+var ErrUnknownConnection = errors.New("unknown connection")
+
+// ErrSpectatorCannotAnswer is returned by SubmitAnswer when the connection
+// submitting an answer is a spectator rather than a player
+var ErrSpectatorCannotAnswer = errors.New("spectators cannot submit answers")
+
+// Role distinguishes a playing connection from a read-only spectator.
+// Spectators receive SQuestion, STimeSync and SScores broadcasts but are
+// excluded from answer marking and name-taken checks
+type Role = uint8
+
+const (
+	RolePlayer    Role = iota // Participates in answering and counts towards name-taken checks
+	RoleSpectator             // Read-only: watches the game without occupying a player slot
+)
+
+// Transport is implemented by whatever holds the actual client connection
+// (backed by gowsps in the full build): it writes already-encoded frame bytes
+// to the client, leaving packet encoding to Connection.Send
+type Transport interface {
+	WriteMessage(data []byte) error
+}
+
+// Connection is a single player or spectator connected to a Game. It encodes
+// each outgoing packet per its own negotiated Capabilities before handing the
+// bytes to its Transport, so binary framing is an independent decision per
+// connection rather than a global one
+type Connection struct {
+	Id        string
+	Name      string
+	Role      Role
+	capsMutex sync.RWMutex
+	caps      Capabilities
+	transport Transport
+	lastScore int32
+}
+
+// Send encodes packet for this connection's negotiated capabilities and
+// writes it to the underlying transport
+func (c *Connection) Send(packet Packet) error {
+	c.capsMutex.RLock()
+	caps := c.caps
+	c.capsMutex.RUnlock()
+
+	data, err := EncodePacket(packet, caps)
+	if err != nil {
+		return err
+	}
+	return c.transport.WriteMessage(data)
+}
+
+// SetCapabilities records the capabilities a connection advertised via its
+// CCapabilities packet, switching its eligible packets to binary framing.
+// It has its own lock rather than relying on a caller already holding
+// Game's mutex, since Send reads it from goroutines that don't
+func (c *Connection) SetCapabilities(caps Capabilities) {
+	c.capsMutex.Lock()
+	defer c.capsMutex.Unlock()
+	c.caps = caps
+}
+
+// Game is a single quiz session: its connections and the award log their
+// scores are folded from
+type Game struct {
+	mutex       sync.Mutex
+	Id          string
+	Title       string
+	Config      GameConfig
+	Events      *EventBus
+	Awards      *AwardLog
+	connections map[string]*Connection
+}
+
+// NewGame creates a new, empty game session using the provided resolved
+// config (see ResolveGameConfig) and award log
+func NewGame(id string, title string, config GameConfig, awards *AwardLog) *Game {
+	return &Game{
+		Id:          id,
+		Title:       title,
+		Config:      config,
+		Events:      NewEventBus(),
+		Awards:      awards,
+		connections: make(map[string]*Connection),
+	}
+}
+
+// NameTaken reports whether name is already in use by a player in the game.
+// Spectators are excluded, so a spectator's display name never blocks a
+// player from joining under the same name
+func (g *Game) NameTaken(name string) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, conn := range g.connections {
+		if conn.Role == RolePlayer && conn.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Join registers a connection with the game under the given role and sends
+// it the appropriate join packet
+func (g *Game) Join(id string, name string, role Role, transport Transport) *Connection {
+	conn := &Connection{Id: id, Name: name, Role: role, transport: transport}
+
+	g.mutex.Lock()
+	g.connections[id] = conn
+	g.mutex.Unlock()
+
+	if role == RoleSpectator {
+		_ = conn.Send(SpectatorJoinPacket(g.Id, g.Title))
+	} else {
+		_ = conn.Send(JoinGamePacket(false, g.Id, g.Title))
+	}
+	_ = conn.Send(GameConfigPacket(g.Config))
+
+	g.Events.Fire(Event{GameId: g.Id, Type: PlayerJoined, Data: struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+		Role Role   `json:"role"`
+	}{Id: id, Name: name, Role: role}})
+
+	return conn
+}
+
+// SetCapabilities records the capabilities a connection advertised via its
+// CCapabilities packet, so its eligible packets switch to binary framing
+func (g *Game) SetCapabilities(connId string, caps Capabilities) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	conn, ok := g.connections[connId]
+	if !ok {
+		return ErrUnknownConnection
+	}
+	conn.SetCapabilities(caps)
+	return nil
+}
+
+// StartQuestion broadcasts the question to every connection, players and
+// spectators alike, followed by an STimeSync packet whose total/remaining
+// durations are derived from the game's GameConfig rather than a fixed value
+func (g *Game) StartQuestion(data tools.QuestionData) {
+	g.Broadcast(QuestionPacket(data))
+	total := g.Config.QuestionCountdownDuration()
+	g.Broadcast(TimeSyncPacket(total, total))
+	g.Events.Fire(Event{GameId: g.Id, Type: QuestionStarted})
+}
+
+// Broadcast sends packet to every connection in the game, players and
+// spectators alike
+func (g *Game) Broadcast(packet Packet) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, conn := range g.connections {
+		_ = conn.Send(packet)
+	}
+}
+
+// BroadcastSpectators computes the aggregated leaderboard, sorted highest
+// score first with each player's delta since the last question, and sends it
+// to every spectator connection
+func (g *Game) BroadcastSpectators() {
+	scores := g.Awards.Scores()
+
+	g.mutex.Lock()
+	entries := make([]SpectatorScore, 0, len(g.connections))
+	spectators := make([]*Connection, 0)
+	for _, conn := range g.connections {
+		if conn.Role != RolePlayer {
+			if conn.Role == RoleSpectator {
+				spectators = append(spectators, conn)
+			}
+			continue
+		}
+		score := scores[conn.Id]
+		entries = append(entries, SpectatorScore{
+			Id:    conn.Id,
+			Name:  conn.Name,
+			Score: uint32(score),
+			Delta: score - conn.lastScore,
+		})
+		conn.lastScore = score
+	}
+	g.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+
+	packet := SpectatorDataPacket(entries)
+	for _, conn := range spectators {
+		_ = conn.Send(packet)
+	}
+}
+
+// RecordAward appends award to the game's log, broadcasts it to every
+// connection, and re-broadcasts the scoreboard folded from the updated log
+func (g *Game) RecordAward(award Award) error {
+	if err := g.Awards.Append(award); err != nil {
+		return err
+	}
+	g.Broadcast(AwardLogPacket(award))
+	g.Broadcast(g.Awards.ScoresPacket())
+	return nil
+}
+
+// SubmitAnswer marks a player's answer using marker, awarding points*score
+// points, and broadcasts the result. Spectators are excluded from answer
+// marking entirely: submitting as a spectator connection returns
+// ErrSpectatorCannotAnswer rather than being scored
+func (g *Game) SubmitAnswer(connId string, marker Marker, clientAnswer any, points int32) (float32, error) {
+	g.mutex.Lock()
+	conn, ok := g.connections[connId]
+	g.mutex.Unlock()
+	if !ok {
+		return 0, ErrUnknownConnection
+	}
+	if conn.Role != RolePlayer {
+		return 0, ErrSpectatorCannotAnswer
+	}
+
+	score, correct := marker.Mark(clientAnswer)
+
+	category := AwardPenalty
+	awardPoints := int32(0)
+	if score > 0 {
+		category = AwardCorrectAnswer
+		awardPoints = int32(float32(points) * score)
+	}
+
+	award := Award{Timestamp: time.Now(), PlayerId: connId, Category: category, Points: awardPoints}
+	if err := g.RecordAward(award); err != nil {
+		return score, err
+	}
+
+	_ = conn.Send(AnswerResultPacket(score, correct))
+	g.Events.Fire(Event{GameId: g.Id, Type: PlayerAnswered, Data: struct {
+		PlayerId string  `json:"playerId"`
+		Score    float32 `json:"score"`
+	}{PlayerId: connId, Score: score}})
+
+	return score, nil
+}
+
+// EndQuestion closes out the current question: it refreshes the spectator
+// leaderboard and fires QuestionEnded
+func (g *Game) EndQuestion() {
+	g.BroadcastSpectators()
+	g.Events.Fire(Event{GameId: g.Id, Type: QuestionEnded})
+}
+
+// End finishes the game: it streams the full award log to every connection
+// for replay/export and fires GameEnded
+func (g *Game) End() {
+	g.Broadcast(ReplayPacket(g.Awards.All()))
+	g.Events.Fire(Event{GameId: g.Id, Type: GameEnded})
+}
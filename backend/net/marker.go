@@ -0,0 +1,178 @@
+package net
+
+import (
+	"sort"
+	"strings"
+)
+
+// toInt normalizes a client answer index. Packets are decoded from JSON into
+// interface{}, so a number always arrives as float64, never int; this accepts
+// both so a Marker works the same whether clientAnswer came over the wire or
+// was constructed directly (e.g. in a test)
+func toInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// toIntSlice normalizes a client answer array. JSON decodes a number array
+// into []interface{}, never []int, so this converts element-by-element and
+// fails if any element isn't a number
+func toIntSlice(value any) ([]int, bool) {
+	switch v := value.(type) {
+	case []int:
+		return v, true
+	case []any:
+		result := make([]int, len(v))
+		for i, element := range v {
+			index, ok := toInt(element)
+			if !ok {
+				return nil, false
+			}
+			result[i] = index
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// ChoiceMarker marks a single-answer multiple choice question: full credit
+// for the matching index, none otherwise
+type ChoiceMarker struct {
+	Correct int
+}
+
+func (m ChoiceMarker) Mark(clientAnswer any) (float32, any) {
+	if answer, ok := toInt(clientAnswer); ok && answer == m.Correct {
+		return 1, m.Correct
+	}
+	return 0, m.Correct
+}
+
+// MultiSelectMarker marks an any-of multiple choice question, awarding
+// partial credit for the proportion of correct indexes selected, less one
+// correct index's worth for each incorrect index selected (never below 0)
+type MultiSelectMarker struct {
+	Correct []int
+}
+
+func (m MultiSelectMarker) Mark(clientAnswer any) (float32, any) {
+	answer, ok := toIntSlice(clientAnswer)
+	if !ok || len(m.Correct) == 0 {
+		return 0, m.Correct
+	}
+
+	correctSet := make(map[int]bool, len(m.Correct))
+	for _, index := range m.Correct {
+		correctSet[index] = true
+	}
+
+	// Dedupe the submitted indexes so repeating a correct one can't be used
+	// to farm extra hits
+	answerSet := make(map[int]bool, len(answer))
+	for _, index := range answer {
+		answerSet[index] = true
+	}
+
+	hits := 0
+	misses := 0
+	for index := range answerSet {
+		if correctSet[index] {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	score := float32(hits-misses) / float32(len(correctSet))
+	if score < 0 {
+		score = 0
+	} else if score > 1 {
+		score = 1
+	}
+	return score, m.Correct
+}
+
+// OrderingMarker marks a ranking question, awarding partial credit for the
+// proportion of positions that match the correct order
+type OrderingMarker struct {
+	Correct []int
+}
+
+func (m OrderingMarker) Mark(clientAnswer any) (float32, any) {
+	answer, ok := toIntSlice(clientAnswer)
+	if !ok || len(m.Correct) == 0 || len(answer) != len(m.Correct) {
+		return 0, m.Correct
+	}
+
+	matches := 0
+	for i, value := range answer {
+		if value == m.Correct[i] {
+			matches++
+		}
+	}
+	return float32(matches) / float32(len(m.Correct)), m.Correct
+}
+
+// NumberRangeMarker marks a numeric answer as correct if it falls within
+// Tolerance of Correct, with partial credit tapering linearly to the edge of
+// the tolerance band
+type NumberRangeMarker struct {
+	Correct   float64
+	Tolerance float64
+}
+
+func (m NumberRangeMarker) Mark(clientAnswer any) (float32, any) {
+	answer, ok := clientAnswer.(float64)
+	if !ok || m.Tolerance <= 0 {
+		return 0, m.Correct
+	}
+
+	diff := answer - m.Correct
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > m.Tolerance {
+		return 0, m.Correct
+	}
+	return float32(1 - diff/m.Tolerance), m.Correct
+}
+
+// FreeTextMarker marks a free-text answer using a case-insensitive fuzzy
+// match: full credit for an exact match (ignoring case/whitespace), partial
+// credit proportional to token overlap otherwise
+type FreeTextMarker struct {
+	Correct string
+}
+
+func (m FreeTextMarker) Mark(clientAnswer any) (float32, any) {
+	answer, ok := clientAnswer.(string)
+	if !ok {
+		return 0, m.Correct
+	}
+
+	normalize := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+	if normalize(answer) == normalize(m.Correct) {
+		return 1, m.Correct
+	}
+
+	answerTokens := strings.Fields(normalize(answer))
+	correctTokens := strings.Fields(normalize(m.Correct))
+	if len(correctTokens) == 0 {
+		return 0, m.Correct
+	}
+
+	sort.Strings(answerTokens)
+	matches := 0
+	for _, token := range correctTokens {
+		i := sort.SearchStrings(answerTokens, token)
+		if i < len(answerTokens) && answerTokens[i] == token {
+			matches++
+		}
+	}
+	return float32(matches) / float32(len(correctTokens)), m.Correct
+}
@@ -0,0 +1,20 @@
+package net
+
+// QuestionKind identifies which answer widget and marking strategy a
+// question uses
+type QuestionKind = uint8
+
+const (
+	KindChoice      QuestionKind = iota // Single-answer multiple choice
+	KindMultiSelect                     // Any-of multiple choice, supports partial credit
+	KindOrdering                        // Rank the answers into the correct order
+	KindNumberRange                     // Numeric answer marked within a tolerance
+	KindFreeText                        // Free-text answer marked with fuzzy matching
+)
+
+// Marker is implemented by each question kind's marking strategy, letting new
+// kinds plug into the game loop without a switch over QuestionKind. Score is
+// in the range 0.0-1.0 and correct is the solution to display to the client
+type Marker interface {
+	Mark(clientAnswer any) (score float32, correct any)
+}
@@ -18,6 +18,16 @@ const (
 	SQuestion            = 0x07
 	SAnswerResult        = 0x08
 	SScores              = 0x09
+	SSpectatorJoined     = 0x0A
+	SSpectatorData       = 0x0B
+	SGameConfig          = 0x0C
+	SAwardLog            = 0x0D
+	SReplay              = 0x0E
+)
+
+// Ids for client packets
+const (
+	CCapabilities int = 0x00
 )
 
 // DisconnectPacket creates a new disconnect packet with the provided reason
@@ -75,7 +85,9 @@ func GameStatePacket(state tools.State) Packet {
 }
 
 // TimeSyncPacket creates a new time sync packet which keeps the current timing
-// of the server countdowns in sync with the clients
+// of the server countdowns in sync with the clients. The total/remaining
+// durations are derived from the game's GameConfig rather than fixed values
+// so that the countdown cadence follows the host's configured timescale
 func TimeSyncPacket(total time.Duration, remaining time.Duration) Packet {
 	return Packet{Id: STimeSync, Data: struct {
 		Total     int64 `json:"total"`
@@ -84,25 +96,32 @@ func TimeSyncPacket(total time.Duration, remaining time.Duration) Packet {
 }
 
 // QuestionPacket creates a new question packet which informs the client which
-// question they are currently answering
+// question they are currently answering. Kind tells the client which answer
+// widget to render (choice, multi-select, ordering, numeric range, free-text)
 func QuestionPacket(data tools.QuestionData) Packet {
 	return Packet{Id: SQuestion, Data: struct {
-		Image    string   `json:"image,omitempty"`
-		Question string   `json:"question"`
-		Answers  []string `json:"answers"`
-	}{Image: data.Image, Question: data.Question, Answers: data.Answers}}
+		Kind     QuestionKind `json:"kind"`
+		Image    string       `json:"image,omitempty"`
+		Question string       `json:"question"`
+		Answers  []string     `json:"answers"`
+	}{Kind: data.Kind, Image: data.Image, Question: data.Question, Answers: data.Answers}}
 }
 
 // AnswerResultPacket creates a new answer result packet which informs the client
-// whether the answer they chose was correct after marking
-func AnswerResultPacket(result bool) Packet {
+// of the score (0.0-1.0) awarded for their answer after marking, along with
+// the correct solution for display. Score is 1.0 for a fully correct answer
+// and 0.0 for a fully incorrect one; kinds that support partial credit (such
+// as multi-select) may return anything in between
+func AnswerResultPacket(score float32, correct any) Packet {
 	return Packet{Id: SAnswerResult, Data: struct {
-		Result bool `json:"result"`
-	}{Result: result}}
+		Score   float32 `json:"score"`
+		Correct any     `json:"correct"`
+	}{Score: score, Correct: correct}}
 }
 
 // ScoresPacket creates a new score packet which contains the scores of all the
-// players in the game. This is sent to everyone when scores change
+// players in the game. This is sent to everyone when scores change. Scores are
+// derived by folding the game's AwardLog rather than tracked separately
 func ScoresPacket(data tools.ScoreMap) Packet {
 	return Packet{Id: SScores, Data: struct {
 		Scores tools.ScoreMap `json:"scores"`
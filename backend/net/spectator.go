@@ -0,0 +1,27 @@
+package net
+
+// SpectatorScore represents a single player's standing within the aggregated
+// leaderboard sent to spectators after each question
+type SpectatorScore struct {
+	Id    string `json:"id"`    // The id of the player
+	Name  string `json:"name"`  // The name of the player
+	Score uint32 `json:"score"` // The player's total score
+	Delta int32  `json:"delta"` // The change in score since the last question
+}
+
+// SpectatorJoinPacket creates a new packet informing a connection that it has
+// joined a game as a spectator rather than a player
+func SpectatorJoinPacket(id string, title string) Packet {
+	return Packet{Id: SSpectatorJoined, Data: struct {
+		Id    string `json:"id"`    // The id of the joined game
+		Title string `json:"title"` // The title of the joined game
+	}{Id: id, Title: title}}
+}
+
+// SpectatorDataPacket creates a new packet containing the aggregated live
+// leaderboard for spectators, sorted from highest to lowest score
+func SpectatorDataPacket(scores []SpectatorScore) Packet {
+	return Packet{Id: SSpectatorData, Data: struct {
+		Scores []SpectatorScore `json:"scores"`
+	}{Scores: scores}}
+}